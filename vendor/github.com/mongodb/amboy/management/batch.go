@@ -0,0 +1,206 @@
+package management
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/pkg/errors"
+)
+
+// batchCoalesceWindow is how long a BatchManager waits after the first
+// caller in a batch arrives before taking its single pass over JobStats.
+// Concurrent callers that show up within this window are served from that
+// one pass instead of each paying for their own scan.
+const batchCoalesceWindow = 50 * time.Millisecond
+
+// BatchManager is an extension to Management for queue implementations
+// that can act on many jobs in a single pass, amortizing the cost of a
+// JobStats scan (and, for remote queues, round trips to the backing store)
+// across every caller whose request arrives within the coalescing window.
+type BatchManager interface {
+	// CompleteJobs marks each of the given job IDs complete in one pass
+	// over the queue, returning which IDs were found and completed and
+	// which were not found.
+	CompleteJobs(ctx context.Context, ids []string) (completed, failed []string, err error)
+
+	// AcquirePending returns up to max pending jobs of the given type,
+	// without handing the same job to two concurrent callers.
+	AcquirePending(ctx context.Context, jobType string, max int) ([]amboy.Job, error)
+}
+
+type completeJobsRequest struct {
+	ids    []string
+	result chan completeJobsResult
+}
+
+type completeJobsResult struct {
+	completed []string
+	failed    []string
+}
+
+type acquirePendingRequest struct {
+	jobType string
+	max     int
+	result  chan acquirePendingResult
+}
+
+type acquirePendingResult struct {
+	jobs []amboy.Job
+}
+
+// CompleteJobs implements BatchManager by coalescing concurrent callers
+// into a single pass over JobStats. The caller's ctx governs only how
+// long it waits for the batch it joined; the scan itself runs detached so
+// that one caller's cancellation cannot starve the others in its batch.
+func (m *queueManager) CompleteJobs(ctx context.Context, ids []string) ([]string, []string, error) {
+	req := &completeJobsRequest{ids: ids, result: make(chan completeJobsResult, 1)}
+
+	m.completeMu.Lock()
+	m.completeBatch = append(m.completeBatch, req)
+	if m.completeTimer == nil {
+		m.completeTimer = time.AfterFunc(batchCoalesceWindow, m.flushCompleteBatch)
+	}
+	m.completeMu.Unlock()
+
+	select {
+	case res := <-req.result:
+		return res.completed, res.failed, nil
+	case <-ctx.Done():
+		return nil, nil, errCanceled(ctx.Err())
+	}
+}
+
+func (m *queueManager) flushCompleteBatch() {
+	m.completeMu.Lock()
+	batch := m.completeBatch
+	m.completeBatch = nil
+	m.completeTimer = nil
+	m.completeMu.Unlock()
+
+	ctx := context.Background()
+
+	wanted := map[string]bool{}
+	for _, req := range batch {
+		for _, id := range req.ids {
+			wanted[id] = true
+		}
+	}
+
+	found := map[string]amboy.Job{}
+	for stat := range m.queue.JobStats(ctx) {
+		if !wanted[stat.ID] {
+			continue
+		}
+		if job, ok := m.queue.Get(ctx, stat.ID); ok {
+			found[stat.ID] = job
+		}
+	}
+
+	for _, job := range found {
+		m.queue.Complete(ctx, job)
+	}
+
+	for _, req := range batch {
+		var completed, failed []string
+		for _, id := range req.ids {
+			if _, ok := found[id]; ok {
+				completed = append(completed, id)
+			} else {
+				failed = append(failed, id)
+			}
+		}
+		req.result <- completeJobsResult{completed: completed, failed: failed}
+	}
+}
+
+// AcquirePending implements BatchManager by coalescing concurrent callers
+// into a single pass over JobStats, and handing each pending job of a
+// given type to at most one caller.
+func (m *queueManager) AcquirePending(ctx context.Context, jobType string, max int) ([]amboy.Job, error) {
+	req := &acquirePendingRequest{jobType: jobType, max: max, result: make(chan acquirePendingResult, 1)}
+
+	m.acquireMu.Lock()
+	m.acquireBatch = append(m.acquireBatch, req)
+	if m.acquireTimer == nil {
+		m.acquireTimer = time.AfterFunc(batchCoalesceWindow, m.flushAcquireBatch)
+	}
+	m.acquireMu.Unlock()
+
+	select {
+	case res := <-req.result:
+		return res.jobs, nil
+	case <-ctx.Done():
+		return nil, errCanceled(ctx.Err())
+	}
+}
+
+func (m *queueManager) flushAcquireBatch() {
+	m.acquireMu.Lock()
+	batch := m.acquireBatch
+	m.acquireBatch = nil
+	m.acquireTimer = nil
+	m.acquireMu.Unlock()
+
+	ctx := context.Background()
+
+	pending := map[string][]amboy.Job{}
+	for stat := range m.queue.JobStats(ctx) {
+		if stat.Completed || stat.InProgress {
+			continue
+		}
+		job, ok := m.queue.Get(ctx, stat.ID)
+		if !ok {
+			continue
+		}
+		jt := job.Type().Name
+		pending[jt] = append(pending[jt], job)
+	}
+
+	for _, req := range batch {
+		pool := pending[req.jobType]
+
+		n := req.max
+		if n <= 0 || n > len(pool) {
+			n = len(pool)
+		}
+
+		acquired := pool[:n]
+		for _, job := range acquired {
+			m.markAcquired(ctx, job)
+		}
+
+		req.result <- acquirePendingResult{jobs: acquired}
+		pending[req.jobType] = pool[n:]
+	}
+}
+
+// markAcquired transitions job to in-progress and persists that change to
+// the queue, so that the job no longer shows up as Pending and a later
+// AcquirePending call (in this batch or the next one) cannot hand it out
+// a second time.
+func (m *queueManager) markAcquired(ctx context.Context, job amboy.Job) {
+	stat := job.Status()
+	stat.InProgress = true
+	stat.ModificationTime = time.Now()
+	job.SetStatus(stat)
+
+	if err := m.queue.Put(ctx, job); err != nil {
+		job.AddError(errors.Wrap(err, "persisting acquired job"))
+	}
+}
+
+// batchState holds the pending-request buffers a queueManager uses to
+// coalesce BatchManager calls. It is embedded in queueManager rather than
+// kept in cachedQueueManager, since the cache already answers reads in
+// O(1) and only mutating operations benefit from batching.
+type batchState struct {
+	completeMu    sync.Mutex
+	completeBatch []*completeJobsRequest
+	completeTimer *time.Timer
+
+	acquireMu    sync.Mutex
+	acquireBatch []*acquirePendingRequest
+	acquireTimer *time.Timer
+}
@@ -0,0 +1,126 @@
+package management
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingQueue wraps mockQueue and counts how many times JobStats is
+// called, so tests can assert that concurrent BatchManager callers are
+// coalesced into a single pass rather than one pass per caller.
+type countingQueue struct {
+	*mockQueue
+	jobStatsCalls int64
+}
+
+func (q *countingQueue) JobStats(ctx context.Context) <-chan amboy.JobStatusInfo {
+	atomic.AddInt64(&q.jobStatsCalls, 1)
+	return q.mockQueue.JobStats(ctx)
+}
+
+func TestCompleteJobsCoalescesConcurrentCallers(t *testing.T) {
+	q := &countingQueue{mockQueue: newMockQueue("a", "b", "c", "d")}
+	m := NewQueueManager(q).(*queueManager)
+
+	var wg sync.WaitGroup
+	ids := [][]string{{"a", "b"}, {"c"}, {"d", "missing"}}
+	results := make([][]string, len(ids))
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			completed, _, err := m.CompleteJobs(context.Background(), ids[i])
+			require.NoError(t, err)
+			results[i] = completed
+		}(i)
+	}
+	wg.Wait()
+
+	assert.ElementsMatch(t, []string{"a", "b"}, results[0])
+	assert.ElementsMatch(t, []string{"c"}, results[1])
+	assert.ElementsMatch(t, []string{"d"}, results[2])
+	assert.EqualValues(t, 1, atomic.LoadInt64(&q.jobStatsCalls))
+
+	for _, id := range []string{"a", "b", "c", "d"} {
+		job, ok := q.Get(context.Background(), id)
+		require.True(t, ok)
+		assert.True(t, job.Status().Completed)
+	}
+}
+
+func TestAcquirePendingDoesNotDoubleAllocate(t *testing.T) {
+	q := &countingQueue{mockQueue: newMockQueue("a", "b", "c")}
+	m := NewQueueManager(q).(*queueManager)
+
+	var wg sync.WaitGroup
+	results := make([][]amboy.Job, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			jobs, err := m.AcquirePending(context.Background(), "mock", 2)
+			require.NoError(t, err)
+			results[i] = jobs
+		}(i)
+	}
+	wg.Wait()
+
+	seen := map[string]int{}
+	for _, jobs := range results {
+		for _, j := range jobs {
+			seen[j.ID()]++
+		}
+	}
+	for id, count := range seen {
+		assert.Equal(t, 1, count, "job %s handed out more than once", id)
+	}
+	assert.EqualValues(t, 1, atomic.LoadInt64(&q.jobStatsCalls))
+}
+
+func TestAcquirePendingMarksJobsInProgress(t *testing.T) {
+	q := newMockQueue("a", "b", "c")
+	m := NewQueueManager(q).(*queueManager)
+
+	first, err := m.AcquirePending(context.Background(), "mock", 2)
+	require.NoError(t, err)
+	assert.Len(t, first, 2)
+
+	for _, j := range first {
+		job, ok := q.Get(context.Background(), j.ID())
+		require.True(t, ok)
+		assert.True(t, job.Status().InProgress, "acquired job %s should be marked in-progress", j.ID())
+	}
+
+	second, err := m.AcquirePending(context.Background(), "mock", 2)
+	require.NoError(t, err)
+	assert.Len(t, second, 1, "only the one job not acquired by the first call should remain pending")
+
+	firstIDs := map[string]bool{}
+	for _, j := range first {
+		firstIDs[j.ID()] = true
+	}
+	for _, j := range second {
+		assert.False(t, firstIDs[j.ID()], "second AcquirePending call returned a job already handed out by the first call")
+	}
+}
+
+func TestBatchManagerReturnsManagementErrorOnCancel(t *testing.T) {
+	q := newMockQueue("a")
+	m := NewQueueManager(q).(*queueManager)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	_, _, err := m.CompleteJobs(ctx, []string{"a"})
+	require.Error(t, err)
+	var managementErr *ManagementError
+	require.ErrorAs(t, err, &managementErr)
+	assert.Equal(t, ErrCanceled, managementErr.Code)
+}
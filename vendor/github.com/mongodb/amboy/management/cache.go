@@ -0,0 +1,399 @@
+package management
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// cachedSnapshot holds the point-in-time view of a queue's job state that a
+// cachedQueueManager serves Management calls from. It is rebuilt from
+// scratch on every refresh and swapped in atomically, so readers never see
+// a partially updated view.
+type cachedSnapshot struct {
+	counters map[CounterFilter]map[string]int
+	ids      map[CounterFilter]map[string][]string
+	runtimes map[string][]jobSample
+	latency  map[string][]jobSample
+	running  map[string][]jobSample
+	errors   map[string][]jobErrorSample
+}
+
+type jobSample struct {
+	at time.Time
+	d  time.Duration
+}
+
+type jobErrorSample struct {
+	at    time.Time
+	count int
+	errs  []string
+}
+
+func newCachedSnapshot() *cachedSnapshot {
+	return &cachedSnapshot{
+		counters: map[CounterFilter]map[string]int{InProgress: {}, Pending: {}, Stale: {}},
+		ids:      map[CounterFilter]map[string][]string{InProgress: {}, Pending: {}, Stale: {}},
+		runtimes: map[string][]jobSample{},
+		latency:  map[string][]jobSample{},
+		running:  map[string][]jobSample{},
+		errors:   map[string][]jobErrorSample{},
+	}
+}
+
+// cachedQueueManager implements Management by answering every call from a
+// snapshot that is refreshed on a fixed interval by a single background
+// goroutine, rather than rescanning the queue's JobStats on every call. See
+// NewCachedQueueManager.
+type cachedQueueManager struct {
+	queue   amboy.Queue
+	refresh time.Duration
+
+	mu       sync.RWMutex
+	snapshot *cachedSnapshot
+
+	closer    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewCachedQueueManager returns a Management implementation that maintains
+// rolling counters and histograms for the queue's job state, updated by a
+// single background goroutine that drains JobStats once per refresh
+// interval. The reporting methods (JobStatus, JobIDsByState, RecentTiming,
+// RecentErrors, RecentJobErrors) answer from that cached snapshot without
+// touching the queue, rather than the scan that NewQueueManager performs on
+// every call; their cost grows with the size of the snapshot. CompleteJob
+// and CompleteJobsByType act on the queue directly, and CompleteJobsByType
+// scans JobStats on every call. This is the preferred manager for queues with large or
+// fast-growing job counts; use NewQueueManager instead when an exact,
+// un-cached view is required.
+//
+// The returned Management also implements PrometheusExporter, exposing the
+// same snapshot as Prometheus metrics.
+func NewCachedQueueManager(q amboy.Queue, refresh time.Duration) Management {
+	m := &cachedQueueManager{
+		queue:    q,
+		refresh:  refresh,
+		snapshot: newCachedSnapshot(),
+		closer:   make(chan struct{}),
+	}
+
+	m.refreshSnapshot(context.Background())
+	go m.refreshLoop()
+
+	return m
+}
+
+func (m *cachedQueueManager) refreshLoop() {
+	ticker := time.NewTicker(m.refresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.refreshSnapshot(context.Background())
+		case <-m.closer:
+			return
+		}
+	}
+}
+
+// Close stops the background refresh goroutine. It does not close the
+// underlying queue.
+func (m *cachedQueueManager) Close() {
+	m.closeOnce.Do(func() { close(m.closer) })
+}
+
+// refreshSnapshot takes a single pass over the queue's JobStats and
+// rebuilds the cached snapshot from it.
+func (m *cachedQueueManager) refreshSnapshot(ctx context.Context) {
+	next := newCachedSnapshot()
+
+	for stat := range m.queue.JobStats(ctx) {
+		job, ok := m.queue.Get(ctx, stat.ID)
+		if !ok {
+			continue
+		}
+		jt := job.Type().Name
+		ti := job.TimeInfo()
+
+		switch {
+		case stat.InProgress && !stat.Completed:
+			next.counters[InProgress][jt]++
+			next.ids[InProgress][jt] = append(next.ids[InProgress][jt], stat.ID)
+
+			next.running[jt] = append(next.running[jt], jobSample{at: time.Now(), d: time.Since(ti.Start)})
+			next.latency[jt] = append(next.latency[jt], jobSample{at: time.Now(), d: time.Since(ti.Created)})
+
+			if time.Since(stat.ModificationTime) > amboy.LockTimeout {
+				next.counters[Stale][jt]++
+				next.ids[Stale][jt] = append(next.ids[Stale][jt], stat.ID)
+			}
+		case !stat.Completed:
+			next.counters[Pending][jt]++
+			next.ids[Pending][jt] = append(next.ids[Pending][jt], stat.ID)
+
+			next.latency[jt] = append(next.latency[jt], jobSample{at: time.Now(), d: time.Since(ti.Created)})
+		case stat.Completed:
+			next.runtimes[jt] = append(next.runtimes[jt], jobSample{at: ti.End, d: ti.End.Sub(ti.Start)})
+
+			if stat.ErrorCount > 0 {
+				next.errors[jt] = append(next.errors[jt], jobErrorSample{at: ti.End, count: stat.ErrorCount, errs: stat.Errors})
+			}
+		}
+	}
+
+	m.mu.Lock()
+	m.snapshot = next
+	m.mu.Unlock()
+}
+
+func (m *cachedQueueManager) get() *cachedSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.snapshot
+}
+
+func (m *cachedQueueManager) JobStatus(ctx context.Context, f CounterFilter) (*JobStatusReport, error) {
+	if err := f.Validate(); err != nil {
+		return nil, errInvalidFilter(string(f), err)
+	}
+
+	snapshot := m.get()
+
+	out := JobStatusReport{Filter: string(f)}
+	for jt, count := range snapshot.counters[f] {
+		out.Stats = append(out.Stats, JobCounters{ID: jt, Count: count})
+	}
+
+	return &out, nil
+}
+
+func (m *cachedQueueManager) JobIDsByState(ctx context.Context, jobType string, f CounterFilter) (*JobReportIDs, error) {
+	if err := f.Validate(); err != nil {
+		return nil, errInvalidFilter(string(f), err)
+	}
+
+	snapshot := m.get()
+
+	var ids []string
+	if jobType != "" {
+		ids = snapshot.ids[f][jobType]
+	} else {
+		for _, typeIDs := range snapshot.ids[f] {
+			ids = append(ids, typeIDs...)
+		}
+	}
+
+	return &JobReportIDs{Filter: string(f), Type: jobType, IDs: ids}, nil
+}
+
+func (m *cachedQueueManager) RecentTiming(ctx context.Context, window time.Duration, f RuntimeFilter) (*JobRuntimeReport, error) {
+	if err := f.Validate(); err != nil {
+		return nil, errInvalidFilter(string(f), err)
+	}
+	if window <= time.Second {
+		return nil, errWindowTooSmall(window)
+	}
+
+	snapshot := m.get()
+
+	var samples map[string][]jobSample
+	switch f {
+	case Duration:
+		samples = snapshot.runtimes
+	case Latency:
+		samples = snapshot.latency
+	case Running:
+		samples = snapshot.running
+	default:
+		return nil, errUnsupported("runtime filter " + string(f))
+	}
+
+	var runtimes []JobRuntimes
+	for jt, all := range samples {
+		var total time.Duration
+		var num int
+		for _, s := range all {
+			if time.Since(s.at) > window {
+				continue
+			}
+			total += s.d
+			num++
+		}
+		if num == 0 {
+			continue
+		}
+		runtimes = append(runtimes, JobRuntimes{ID: jt, Duration: total / time.Duration(num)})
+	}
+
+	return &JobRuntimeReport{Filter: string(f), Period: window, Stats: runtimes}, nil
+}
+
+func (m *cachedQueueManager) recentErrors(window time.Duration, f ErrorFilter, jobType string) (*JobErrorsReport, error) {
+	if err := f.Validate(); err != nil {
+		return nil, errInvalidFilter(string(f), err)
+	}
+	if window <= time.Second {
+		return nil, errWindowTooSmall(window)
+	}
+
+	snapshot := m.get()
+
+	var reports []JobErrorsForType
+	for jt, samples := range snapshot.errors {
+		if jobType != "" && jt != jobType {
+			continue
+		}
+
+		val := JobErrorsForType{ID: jt}
+		uniq := map[string]struct{}{}
+		for _, s := range samples {
+			if time.Since(s.at) > window {
+				continue
+			}
+			val.Count++
+			val.Total += s.count
+			if f != StatsOnly {
+				val.Errors = append(val.Errors, s.errs...)
+			}
+			for _, e := range s.errs {
+				uniq[e] = struct{}{}
+			}
+		}
+		if val.Count == 0 {
+			continue
+		}
+		if f == UniqueErrors {
+			val.Errors = val.Errors[:0]
+			for e := range uniq {
+				val.Errors = append(val.Errors, e)
+			}
+		}
+		val.Average = float64(val.Total / val.Count)
+
+		reports = append(reports, val)
+	}
+
+	return &JobErrorsReport{Period: window, FilteredByType: jobType != "", Data: reports}, nil
+}
+
+func (m *cachedQueueManager) RecentErrors(ctx context.Context, window time.Duration, f ErrorFilter) (*JobErrorsReport, error) {
+	return m.recentErrors(window, f, "")
+}
+
+func (m *cachedQueueManager) RecentJobErrors(ctx context.Context, jobType string, window time.Duration, f ErrorFilter) (*JobErrorsReport, error) {
+	return m.recentErrors(window, f, jobType)
+}
+
+func (m *cachedQueueManager) CompleteJob(ctx context.Context, name string) error {
+	j, exists := m.queue.Get(ctx, name)
+	if !exists {
+		return errJobNotFound(name)
+	}
+
+	m.queue.Complete(ctx, j)
+	return nil
+}
+
+func (m *cachedQueueManager) CompleteJobsByType(ctx context.Context, jobType string) error {
+	for stat := range m.queue.JobStats(ctx) {
+		if stat.Completed {
+			continue
+		}
+
+		job, ok := m.queue.Get(ctx, stat.ID)
+		if ok && job.Type().Name != jobType {
+			continue
+		}
+		m.queue.Complete(ctx, job)
+	}
+
+	return nil
+}
+
+// PrometheusExporter is an optional extension to Management, implemented by
+// managers that can report their cached job state as Prometheus metrics
+// without an additional scan of the underlying queue.
+type PrometheusExporter interface {
+	PrometheusCollector() prometheus.Collector
+}
+
+// PrometheusCollector returns a prometheus.Collector that exports
+// in-progress/pending/stale gauges, a job-duration/latency histogram, and
+// an error counter, all labeled by job type and all sourced from the same
+// cached snapshot the Management methods read from.
+func (m *cachedQueueManager) PrometheusCollector() prometheus.Collector {
+	return &managementCollector{manager: m}
+}
+
+type managementCollector struct {
+	manager *cachedQueueManager
+}
+
+var (
+	managementJobCountDesc = prometheus.NewDesc(
+		"amboy_management_jobs",
+		"Number of jobs in the given state, labeled by job type.",
+		[]string{"type", "state"}, nil,
+	)
+	managementJobDurationDesc = prometheus.NewDesc(
+		"amboy_management_job_duration_seconds",
+		"Completed job durations, labeled by job type.",
+		[]string{"type"}, nil,
+	)
+	managementJobLatencyDesc = prometheus.NewDesc(
+		"amboy_management_job_latency_seconds",
+		"Time jobs spend queued and running before completion, labeled by job type.",
+		[]string{"type"}, nil,
+	)
+	managementJobErrorsDesc = prometheus.NewDesc(
+		"amboy_management_job_errors_total",
+		"Number of errors reported by completed jobs, labeled by job type.",
+		[]string{"type"}, nil,
+	)
+)
+
+func (c *managementCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- managementJobCountDesc
+	ch <- managementJobDurationDesc
+	ch <- managementJobLatencyDesc
+	ch <- managementJobErrorsDesc
+}
+
+func (c *managementCollector) Collect(ch chan<- prometheus.Metric) {
+	snapshot := c.manager.get()
+
+	for _, f := range []CounterFilter{InProgress, Pending, Stale} {
+		for jt, count := range snapshot.counters[f] {
+			ch <- prometheus.MustNewConstMetric(managementJobCountDesc, prometheus.GaugeValue, float64(count), jt, string(f))
+		}
+	}
+
+	for jt, samples := range snapshot.runtimes {
+		var sum float64
+		for _, s := range samples {
+			sum += s.d.Seconds()
+		}
+		ch <- prometheus.MustNewConstHistogram(managementJobDurationDesc, uint64(len(samples)), sum, map[float64]uint64{}, jt)
+	}
+
+	for jt, samples := range snapshot.latency {
+		var sum float64
+		for _, s := range samples {
+			sum += s.d.Seconds()
+		}
+		ch <- prometheus.MustNewConstHistogram(managementJobLatencyDesc, uint64(len(samples)), sum, map[float64]uint64{}, jt)
+	}
+
+	for jt, samples := range snapshot.errors {
+		var total int
+		for _, s := range samples {
+			total += s.count
+		}
+		ch <- prometheus.MustNewConstMetric(managementJobErrorsDesc, prometheus.CounterValue, float64(total), jt)
+	}
+}
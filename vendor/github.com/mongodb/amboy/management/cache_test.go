@@ -0,0 +1,152 @@
+package management
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCachedManagerForTest(q amboy.Queue) *cachedQueueManager {
+	m := &cachedQueueManager{
+		queue:    q,
+		refresh:  time.Hour,
+		snapshot: newCachedSnapshot(),
+		closer:   make(chan struct{}),
+	}
+	m.refreshSnapshot(context.Background())
+	return m
+}
+
+func TestCachedQueueManagerJobStatus(t *testing.T) {
+	q := newMockQueue("pending-1", "running-1")
+	running, ok := q.Get(context.Background(), "running-1")
+	require.True(t, ok)
+	running.SetStatus(amboy.JobStatusInfo{ID: "running-1", InProgress: true, ModificationTime: time.Now()})
+
+	m := newCachedManagerForTest(q)
+	defer m.Close()
+
+	report, err := m.JobStatus(context.Background(), Pending)
+	require.NoError(t, err)
+	require.Len(t, report.Stats, 1)
+	assert.Equal(t, 1, report.Stats[0].Count)
+
+	report, err = m.JobStatus(context.Background(), InProgress)
+	require.NoError(t, err)
+	require.Len(t, report.Stats, 1)
+	assert.Equal(t, 1, report.Stats[0].Count)
+}
+
+func TestCachedQueueManagerJobStatusRejectsInvalidFilter(t *testing.T) {
+	m := newCachedManagerForTest(newMockQueue())
+	defer m.Close()
+
+	_, err := m.JobStatus(context.Background(), CounterFilter("bogus"))
+	require.Error(t, err)
+	var managementErr *ManagementError
+	require.ErrorAs(t, err, &managementErr)
+	assert.Equal(t, ErrInvalidFilter, managementErr.Code)
+}
+
+func TestCachedQueueManagerRecentTimingLatencyDiffersFromDuration(t *testing.T) {
+	q := newMockQueue("done", "pending")
+
+	done, ok := q.Get(context.Background(), "done")
+	require.True(t, ok)
+	start := time.Now().Add(-time.Minute)
+	end := time.Now().Add(-30 * time.Second)
+	done.SetStatus(amboy.JobStatusInfo{ID: "done", Completed: true})
+	done.(*mockJob).ti = amboy.JobTimeInfo{Created: start, Start: start, End: end}
+
+	pending, ok := q.Get(context.Background(), "pending")
+	require.True(t, ok)
+	created := time.Now().Add(-5 * time.Minute)
+	pending.(*mockJob).ti = amboy.JobTimeInfo{Created: created, Start: created, End: created}
+
+	m := newCachedManagerForTest(q)
+	defer m.Close()
+
+	durationReport, err := m.RecentTiming(context.Background(), time.Minute, Duration)
+	require.NoError(t, err)
+	require.Len(t, durationReport.Stats, 1)
+	assert.InDelta(t, 30*time.Second, durationReport.Stats[0].Duration, float64(time.Second))
+
+	latencyReport, err := m.RecentTiming(context.Background(), time.Minute, Latency)
+	require.NoError(t, err)
+	require.Len(t, latencyReport.Stats, 1)
+	assert.InDelta(t, 5*time.Minute, latencyReport.Stats[0].Duration, float64(time.Second))
+
+	assert.NotEqual(t, durationReport.Stats[0].Duration, latencyReport.Stats[0].Duration,
+		"Latency must not report the same numbers as Duration")
+}
+
+func TestCachedQueueManagerRecentErrorsAverages(t *testing.T) {
+	q := newMockQueue("job-1")
+	job, ok := q.Get(context.Background(), "job-1")
+	require.True(t, ok)
+	job.SetStatus(amboy.JobStatusInfo{ID: "job-1", Completed: true, ErrorCount: 2, Errors: []string{"boom", "boom"}})
+
+	m := newCachedManagerForTest(q)
+	defer m.Close()
+
+	report, err := m.RecentErrors(context.Background(), time.Minute, UniqueErrors)
+	require.NoError(t, err)
+	require.Len(t, report.Data, 1)
+	assert.Equal(t, 1, report.Data[0].Count)
+	assert.Equal(t, 2, report.Data[0].Total)
+	assert.Equal(t, float64(2), report.Data[0].Average)
+	assert.Equal(t, []string{"boom"}, report.Data[0].Errors)
+}
+
+func TestCachedQueueManagerPrometheusCollector(t *testing.T) {
+	q := newMockQueue("pending-1")
+
+	m := newCachedManagerForTest(q)
+	defer m.Close()
+
+	exporter, ok := Management(m).(PrometheusExporter)
+	require.True(t, ok)
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(exporter.PrometheusCollector()))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	var found, foundLatency bool
+	for _, fam := range families {
+		switch fam.GetName() {
+		case "amboy_management_jobs":
+			for _, metric := range fam.GetMetric() {
+				if metricHasLabel(metric, "state", string(Pending)) {
+					found = true
+					assert.Equal(t, float64(1), metric.GetGauge().GetValue())
+				}
+			}
+		case "amboy_management_job_latency_seconds":
+			for _, metric := range fam.GetMetric() {
+				if metricHasLabel(metric, "type", "mock") {
+					foundLatency = true
+					assert.Equal(t, uint64(1), metric.GetHistogram().GetSampleCount())
+				}
+			}
+		}
+	}
+	assert.True(t, found, "expected a pending-state amboy_management_jobs gauge")
+	assert.True(t, foundLatency, "expected a mock-type amboy_management_job_latency_seconds histogram")
+}
+
+func metricHasLabel(m *dto.Metric, name, value string) bool {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name && l.GetValue() == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package management
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrorCode classifies the ways a Management method can fail, so that
+// callers above this package (REST handlers, graphql resolvers) can branch
+// on the failure mode instead of string-matching Error().
+type ErrorCode string
+
+const (
+	// ErrInvalidFilter indicates a CounterFilter, RuntimeFilter, or
+	// ErrorFilter value that failed its own Validate().
+	ErrInvalidFilter ErrorCode = "invalid-filter"
+	// ErrWindowTooSmall indicates a reporting window at or below the
+	// minimum supported duration.
+	ErrWindowTooSmall ErrorCode = "window-too-small"
+	// ErrJobNotFound indicates a job name that does not exist in the
+	// queue.
+	ErrJobNotFound ErrorCode = "job-not-found"
+	// ErrUnsupported indicates a filter or operation this Management
+	// implementation does not (or no longer) support.
+	ErrUnsupported ErrorCode = "unsupported"
+	// ErrCanceled indicates a BatchManager call's context was canceled or
+	// timed out before the batch it joined was flushed.
+	ErrCanceled ErrorCode = "canceled"
+)
+
+// httpStatus maps each ErrorCode to the status a REST handler should
+// respond with.
+var httpStatus = map[ErrorCode]int{
+	ErrInvalidFilter:  http.StatusBadRequest,
+	ErrWindowTooSmall: http.StatusBadRequest,
+	ErrJobNotFound:    http.StatusNotFound,
+	ErrUnsupported:    http.StatusNotImplemented,
+	ErrCanceled:       http.StatusGatewayTimeout,
+}
+
+// ManagementError is the error type every method on the Management
+// implementations in this package returns. Callers can use errors.As to
+// recover it and inspect Code/HTTPStatus/Details, rather than matching on
+// Error() strings.
+type ManagementError struct {
+	Code       ErrorCode
+	Message    string
+	HTTPStatus int
+	Details    map[string]interface{}
+}
+
+func (e *ManagementError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Is reports whether target is a ManagementError with the same Code, so
+// that errors.Is(err, &ManagementError{Code: ErrJobNotFound}) works without
+// requiring Message or Details to match.
+func (e *ManagementError) Is(target error) bool {
+	other, ok := target.(*ManagementError)
+	if !ok {
+		return false
+	}
+	return other.Code == e.Code
+}
+
+func newManagementError(code ErrorCode, msg string, details map[string]interface{}) *ManagementError {
+	return &ManagementError{
+		Code:       code,
+		Message:    msg,
+		HTTPStatus: httpStatus[code],
+		Details:    details,
+	}
+}
+
+// errInvalidFilter reports that filter failed Validate(); reason, if
+// non-nil, is the error Validate() returned.
+func errInvalidFilter(filter string, reason error) *ManagementError {
+	details := map[string]interface{}{"filter": filter}
+	msg := fmt.Sprintf("invalid filter %q", filter)
+	if reason != nil {
+		details["reason"] = reason.Error()
+		msg = fmt.Sprintf("%s: %s", msg, reason.Error())
+	}
+
+	return newManagementError(ErrInvalidFilter, msg, details)
+}
+
+// errWindowTooSmall reports that a caller requested a reporting window at
+// or below the minimum supported duration.
+func errWindowTooSmall(window time.Duration) *ManagementError {
+	return newManagementError(ErrWindowTooSmall,
+		"must specify windows greater than one second",
+		map[string]interface{}{"window": window.String()})
+}
+
+// errJobNotFound reports that name does not refer to a job the queue
+// knows about.
+func errJobNotFound(name string) *ManagementError {
+	return newManagementError(ErrJobNotFound,
+		fmt.Sprintf("cannot recover job with name '%s'", name),
+		map[string]interface{}{"name": name})
+}
+
+// errUnsupported reports that operation is not implemented by this
+// Management method.
+func errUnsupported(operation string) *ManagementError {
+	return newManagementError(ErrUnsupported,
+		"operation is not supported",
+		map[string]interface{}{"operation": operation})
+}
+
+// errCanceled reports that a BatchManager call's context ended before the
+// batch it joined was flushed.
+func errCanceled(reason error) *ManagementError {
+	return newManagementError(ErrCanceled,
+		fmt.Sprintf("batch request canceled: %s", reason.Error()),
+		map[string]interface{}{"reason": reason.Error()})
+}
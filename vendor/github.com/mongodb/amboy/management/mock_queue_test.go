@@ -0,0 +1,139 @@
+package management
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mongodb/amboy"
+)
+
+// mockJob is a minimal amboy.Job used to exercise the management package
+// without a real queue backend.
+type mockJob struct {
+	id   string
+	mu   sync.Mutex
+	stat amboy.JobStatusInfo
+	ti   amboy.JobTimeInfo
+	ri   amboy.JobRetryInfo
+}
+
+func newMockJob(id string) *mockJob {
+	now := time.Now()
+	return &mockJob{
+		id: id,
+		ti: amboy.JobTimeInfo{Created: now, Start: now, End: now},
+	}
+}
+
+func (j *mockJob) ID() string                  { return j.id }
+func (j *mockJob) Type() amboy.JobType         { return amboy.JobType{Name: "mock"} }
+func (j *mockJob) TimeInfo() amboy.JobTimeInfo { return j.ti }
+
+func (j *mockJob) RetryInfo() amboy.JobRetryInfo {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.ri
+}
+
+func (j *mockJob) SetRetryInfo(ri amboy.JobRetryInfo) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.ri = ri
+}
+func (j *mockJob) AddError(err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err == nil {
+		return
+	}
+	j.stat.ErrorCount++
+	j.stat.Errors = append(j.stat.Errors, err.Error())
+}
+
+func (j *mockJob) Status() amboy.JobStatusInfo {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.stat
+}
+
+func (j *mockJob) SetStatus(stat amboy.JobStatusInfo) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.stat = stat
+}
+
+// mockQueue is a minimal in-memory amboy.Queue backed by a map, used to
+// exercise the batching and caching behavior in this package without a
+// real MongoDB-backed queue.
+type mockQueue struct {
+	mu   sync.Mutex
+	jobs map[string]*mockJob
+}
+
+func newMockQueue(ids ...string) *mockQueue {
+	q := &mockQueue{jobs: map[string]*mockJob{}}
+	for _, id := range ids {
+		q.jobs[id] = newMockJob(id)
+	}
+	return q
+}
+
+func (q *mockQueue) Put(_ context.Context, j amboy.Job) error {
+	mj, ok := j.(*mockJob)
+	if !ok {
+		mj = newMockJob(j.ID())
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.jobs[mj.ID()] = mj
+	return nil
+}
+
+func (q *mockQueue) Get(_ context.Context, id string) (amboy.Job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	j, ok := q.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	return j, true
+}
+
+func (q *mockQueue) Complete(_ context.Context, j amboy.Job) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if mj, ok := q.jobs[j.ID()]; ok {
+		stat := mj.Status()
+		stat.Completed = true
+		stat.InProgress = false
+		mj.SetStatus(stat)
+	}
+}
+
+func (q *mockQueue) JobStats(ctx context.Context) <-chan amboy.JobStatusInfo {
+	out := make(chan amboy.JobStatusInfo)
+
+	q.mu.Lock()
+	jobs := make([]*mockJob, 0, len(q.jobs))
+	for _, j := range q.jobs {
+		jobs = append(jobs, j)
+	}
+	q.mu.Unlock()
+
+	go func() {
+		defer close(out)
+		for _, j := range jobs {
+			stat := j.Status()
+			stat.ID = j.ID()
+			select {
+			case out <- stat:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
@@ -5,11 +5,12 @@ import (
 	"time"
 
 	"github.com/mongodb/amboy"
-	"github.com/pkg/errors"
 )
 
 type queueManager struct {
 	queue amboy.Queue
+
+	batchState
 }
 
 // NewQueueManager returns a queue manager that provides the supported
@@ -29,7 +30,7 @@ func NewQueueManager(q amboy.Queue) Management {
 
 func (m *queueManager) JobStatus(ctx context.Context, f CounterFilter) (*JobStatusReport, error) {
 	if err := f.Validate(); err != nil {
-		return nil, errors.WithStack(err)
+		return nil, errInvalidFilter(string(f), err)
 	}
 
 	var cancel context.CancelFunc
@@ -85,11 +86,11 @@ func (m *queueManager) RecentTiming(ctx context.Context, window time.Duration, f
 	var err error
 
 	if err = f.Validate(); err != nil {
-		return nil, errors.WithStack(err)
+		return nil, errInvalidFilter(string(f), err)
 	}
 
 	if window <= time.Second {
-		return nil, errors.New("must specify windows greater than one second")
+		return nil, errWindowTooSmall(window)
 	}
 
 	counters := map[string][]time.Duration{}
@@ -133,7 +134,7 @@ func (m *queueManager) RecentTiming(ctx context.Context, window time.Duration, f
 			}
 		}
 	default:
-		return nil, errors.New("invalid job runtime filter")
+		return nil, errUnsupported("runtime filter " + string(f))
 	}
 
 	runtimes := []JobRuntimes{}
@@ -160,7 +161,7 @@ func (m *queueManager) RecentTiming(ctx context.Context, window time.Duration, f
 func (m *queueManager) JobIDsByState(ctx context.Context, jobType string, f CounterFilter) (*JobReportIDs, error) {
 	var err error
 	if err = f.Validate(); err != nil {
-		return nil, errors.WithStack(err)
+		return nil, errInvalidFilter(string(f), err)
 	}
 
 	// it might be the case that we shold use something with
@@ -210,7 +211,7 @@ func (m *queueManager) JobIDsByState(ctx context.Context, jobType string, f Coun
 			}
 		}
 	default:
-		return nil, errors.New("invalid job status filter")
+		return nil, errUnsupported("job status filter " + string(f))
 	}
 
 	return &JobReportIDs{
@@ -223,11 +224,10 @@ func (m *queueManager) JobIDsByState(ctx context.Context, jobType string, f Coun
 func (m *queueManager) RecentErrors(ctx context.Context, window time.Duration, f ErrorFilter) (*JobErrorsReport, error) {
 	var err error
 	if err = f.Validate(); err != nil {
-		return nil, errors.WithStack(err)
-
+		return nil, errInvalidFilter(string(f), err)
 	}
 	if window <= time.Second {
-		return nil, errors.New("must specify windows greater than one second")
+		return nil, errWindowTooSmall(window)
 	}
 
 	collector := map[string]JobErrorsForType{}
@@ -317,7 +317,7 @@ func (m *queueManager) RecentErrors(ctx context.Context, window time.Duration, f
 			}
 		}
 	default:
-		return nil, errors.New("operation is not supported")
+		return nil, errUnsupported("error filter " + string(f))
 	}
 
 	var reports []JobErrorsForType
@@ -339,11 +339,10 @@ func (m *queueManager) RecentErrors(ctx context.Context, window time.Duration, f
 func (m *queueManager) RecentJobErrors(ctx context.Context, jobType string, window time.Duration, f ErrorFilter) (*JobErrorsReport, error) {
 	var err error
 	if err = f.Validate(); err != nil {
-		return nil, errors.WithStack(err)
-
+		return nil, errInvalidFilter(string(f), err)
 	}
 	if window <= time.Second {
-		return nil, errors.New("must specify windows greater than one second")
+		return nil, errWindowTooSmall(window)
 	}
 
 	collector := map[string]JobErrorsForType{}
@@ -439,7 +438,7 @@ func (m *queueManager) RecentJobErrors(ctx context.Context, jobType string, wind
 			}
 		}
 	default:
-		return nil, errors.New("operation is not supported")
+		return nil, errUnsupported("error filter " + string(f))
 	}
 
 	var reports []JobErrorsForType
@@ -462,7 +461,7 @@ func (m *queueManager) RecentJobErrors(ctx context.Context, jobType string, wind
 func (m *queueManager) CompleteJob(ctx context.Context, name string) error {
 	j, exists := m.queue.Get(ctx, name)
 	if !exists {
-		return errors.Errorf("cannot recover job with name '%s'", name)
+		return errJobNotFound(name)
 	}
 
 	m.queue.Complete(ctx, j)
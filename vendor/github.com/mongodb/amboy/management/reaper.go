@@ -0,0 +1,202 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mongodb/amboy"
+	"github.com/pkg/errors"
+)
+
+// staleBurstThreshold is the number of stale jobs of a single type observed
+// in one reap cycle at or above which StaleJobReaper treats that type as
+// bursting and starts backing off, rather than immediately retrying into
+// what is likely a broken worker pool. Backoff is tracked per type, but the
+// longest backoff of any type sets the wait before the reaper's next cycle
+// for all types.
+const staleBurstThreshold = 10
+
+// DeadLetterSink archives jobs that StaleJobReaper has given up retrying.
+// Implementations might write the job to a collection, emit an event, or
+// page an on-call rotation.
+type DeadLetterSink interface {
+	Handle(ctx context.Context, job amboy.Job, reason string) error
+}
+
+// StaleJobReaper periodically asks a Management for the set of stale jobs
+// and attempts to recover them: jobs that declare themselves retryable are
+// re-enqueued, up to maxRetries attempts. Jobs that are not retryable or
+// have exhausted their retries are forwarded to the DeadLetterSink, if one
+// is configured, and marked complete; when there is no sink, or the sink
+// returns an error, they are marked complete with an explanatory error.
+type StaleJobReaper struct {
+	manager    Management
+	queue      amboy.Queue
+	sink       DeadLetterSink
+	interval   time.Duration
+	maxRetries int
+
+	mu      sync.Mutex
+	backoff map[string]time.Duration
+
+	closer    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewStaleJobReaper constructs a reaper for the given queue. manager is
+// used to enumerate stale jobs; it is typically the same queue's
+// Management, but may be a cached manager to keep reaping cheap. interval
+// is the normal time between reap cycles, before any per-type backoff is
+// applied; maxRetries is the number of times a retryable job is
+// re-enqueued before it is considered exhausted.
+func NewStaleJobReaper(manager Management, queue amboy.Queue, sink DeadLetterSink, interval time.Duration, maxRetries int) *StaleJobReaper {
+	return &StaleJobReaper{
+		manager:    manager,
+		queue:      queue,
+		sink:       sink,
+		interval:   interval,
+		maxRetries: maxRetries,
+		backoff:    map[string]time.Duration{},
+		closer:     make(chan struct{}),
+	}
+}
+
+// Start launches the reaper's background loop. The loop runs until ctx is
+// canceled or Close is called.
+func (r *StaleJobReaper) Start(ctx context.Context) {
+	go r.run(ctx)
+}
+
+// Close stops the reaper's background loop.
+func (r *StaleJobReaper) Close() {
+	r.closeOnce.Do(func() { close(r.closer) })
+}
+
+func (r *StaleJobReaper) run(ctx context.Context) {
+	timer := time.NewTimer(r.interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.closer:
+			return
+		case <-timer.C:
+			timer.Reset(r.reapOnce(ctx))
+		}
+	}
+}
+
+// reapOnce runs a single reap cycle and returns how long to wait before
+// the next one, which may be longer than r.interval if a job type is
+// producing a burst of stale jobs.
+func (r *StaleJobReaper) reapOnce(ctx context.Context) time.Duration {
+	ids, err := r.manager.JobIDsByState(ctx, "", Stale)
+	if err != nil {
+		return r.interval
+	}
+
+	if len(ids.IDs) == 0 {
+		r.mu.Lock()
+		r.backoff = map[string]time.Duration{}
+		r.mu.Unlock()
+		return r.interval
+	}
+
+	byType := map[string][]string{}
+	for _, id := range ids.IDs {
+		job, ok := r.queue.Get(ctx, id)
+		if !ok {
+			continue
+		}
+		jt := job.Type().Name
+		byType[jt] = append(byType[jt], id)
+	}
+
+	wait := r.interval
+	for jt, typeIDs := range byType {
+		for _, id := range typeIDs {
+			r.reapJob(ctx, id)
+		}
+
+		if next := r.nextBackoff(jt, len(typeIDs)); next > wait {
+			wait = next
+		}
+	}
+
+	return wait
+}
+
+// nextBackoff doubles the wait before the next reap cycle for a job type
+// each cycle it continues to produce a burst of stale jobs, and resets it
+// once the burst subsides. This keeps a single broken worker pool from
+// causing repeated stampedes of re-enqueues.
+func (r *StaleJobReaper) nextBackoff(jobType string, numStale int) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if numStale < staleBurstThreshold {
+		delete(r.backoff, jobType)
+		return r.interval
+	}
+
+	wait := r.backoff[jobType]
+	if wait == 0 {
+		wait = r.interval
+	} else {
+		wait *= 2
+	}
+	r.backoff[jobType] = wait
+
+	return wait
+}
+
+// reapJob attempts to recover a single stale job. The attempt counter
+// lives on the job's own RetryInfo (not in reaper-local state), so it
+// survives reaper restarts and is visible to any other reaper or process
+// sharing the same underlying queue.
+func (r *StaleJobReaper) reapJob(ctx context.Context, id string) {
+	job, ok := r.queue.Get(ctx, id)
+	if !ok {
+		return
+	}
+
+	retry := job.RetryInfo()
+	retry.CurrentAttempt++
+	attempt := retry.CurrentAttempt
+	job.SetRetryInfo(retry)
+
+	if retry.Retryable && attempt <= r.maxRetries {
+		// Clear the stale lock before re-enqueuing: leaving InProgress
+		// set (with the same ModificationTime) would make the job
+		// look Stale again on the very next reap cycle, without it
+		// ever having had a real chance to re-run.
+		stat := job.Status()
+		stat.InProgress = false
+		stat.ModificationTime = time.Now()
+		job.SetStatus(stat)
+
+		if err := r.queue.Put(ctx, job); err != nil {
+			job.AddError(errors.Wrap(err, "re-enqueuing stale job"))
+		}
+		return
+	}
+
+	reason := fmt.Sprintf("aborted after %d stale detections", attempt)
+
+	if r.sink != nil {
+		if err := r.sink.Handle(ctx, job, reason); err == nil {
+			// The sink has taken ownership of the job; still mark it
+			// complete so it stops being reported Stale and dead-lettered
+			// again on every subsequent reap cycle.
+			r.queue.Complete(ctx, job)
+			return
+		}
+	}
+
+	job.AddError(errors.New(reason))
+	r.queue.Complete(ctx, job)
+}